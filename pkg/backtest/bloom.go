@@ -0,0 +1,85 @@
+package backtest
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/bits-and-blooms/bloom/v3"
+)
+
+// bloomEstimatedItems and bloomFalsePositiveRate size each symbol's
+// filter per the standard bits-and-blooms/bloom sizing guidance: enough
+// buckets for a symbol trading continuously for years at BloomGranularity,
+// at a ~1% false-positive rate.
+//
+// There is deliberately no on-disk format version here. A symbolBloom is
+// rebuilt from scratch on Reset() and is never serialized or read back
+// across process runs, so there is no stale format to invalidate against
+// and a version field guarding that case would be dead weight. If
+// symbolBloom ever gains persistence (e.g. to skip rebuilding on warm
+// restart), reintroduce a version and check it on load.
+const (
+	bloomEstimatedItems    = 200_000
+	bloomFalsePositiveRate = 0.01
+)
+
+// BloomGranularity is the bucket width event timestamps are truncated to
+// before being recorded in a symbol's bloom filter. Coarser granularity
+// means fewer distinct buckets and a cheaper, more reusable filter;
+// finer granularity narrows the window Bloom can rule out activity in,
+// at the cost of more buckets.
+var BloomGranularity = time.Hour
+
+// symbolBloom is one symbol's rolling bloom filter of traded buckets.
+type symbolBloom struct {
+	filter *bloom.BloomFilter
+}
+
+func newSymbolBloom() *symbolBloom {
+	return &symbolBloom{
+		filter: bloom.NewWithEstimates(bloomEstimatedItems, bloomFalsePositiveRate),
+	}
+}
+
+// bloomKey encodes t's bucket as the filter item key.
+func bloomKey(t time.Time) []byte {
+	bucket := t.Truncate(BloomGranularity).Unix()
+	return []byte(strconv.FormatInt(bucket, 10))
+}
+
+// recordBloom adds event's bucket to its symbol's bloom filter.
+func (d *Data) recordBloom(event DataEvent) {
+	if d.blooms == nil {
+		d.blooms = make(map[string]*symbolBloom)
+	}
+
+	b, ok := d.blooms[event.Symbol()]
+	if !ok {
+		b = newSymbolBloom()
+		d.blooms[event.Symbol()] = b
+	}
+
+	b.filter.Add(bloomKey(event.Time()))
+}
+
+// Bloom reports whether symbol may have traded at all within [from, to],
+// checked bucket by bucket from from's bucket up to and including to's.
+// A false result is definitive: the symbol never traded in the window.
+// A true result means it probably did, at Bloom's false-positive rate,
+// and should be confirmed with List(symbol) before being relied on for
+// anything but a cheap pre-filter. This lets strategies scanning
+// thousands of symbols skip the ones with no activity in a lookback
+// window before paying for a linear List scan.
+func (d *Data) Bloom(symbol string, from, to time.Time) bool {
+	b, ok := d.blooms[symbol]
+	if !ok {
+		return false
+	}
+
+	for t := from.Truncate(BloomGranularity); !t.After(to); t = t.Add(BloomGranularity) {
+		if b.filter.Test(bloomKey(t)) {
+			return true
+		}
+	}
+	return false
+}
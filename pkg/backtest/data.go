@@ -1,7 +1,10 @@
 package backtest
 
 import (
+	"container/heap"
+	"fmt"
 	"sort"
+	"time"
 )
 
 // DataHandler is the combined data interface.
@@ -25,12 +28,223 @@ type DataStreamer interface {
 	List(string) []DataEvent
 }
 
+// BarEvent is implemented by DataEvents that carry OHLC bar data. Data can
+// explode a BarEvent into intra-bar sub-events instead of delivering it as
+// a single bar-close event.
+type BarEvent interface {
+	DataEvent
+	Open() float64
+	High() float64
+	Low() float64
+	Close() float64
+}
+
+// TickBarEvent is implemented by BarEvents that can also supply the
+// arbitrary intra-bar ticks (e.g. trade prints) they were built from,
+// instead of only the O/H/L/C summary.
+type TickBarEvent interface {
+	BarEvent
+	Ticks() []DataEvent
+}
+
+// StreamMode controls how Data.Next exposes the events within a bar.
+type StreamMode int
+
+const (
+	// ModeBarClose emits one event per bar, at its close. This is the
+	// original behaviour: a strategy only ever sees a bar once it has
+	// fully closed.
+	ModeBarClose StreamMode = iota
+	// ModeOHLC explodes each BarEvent into four sub-events delivered in
+	// open, high, low, close order, so stop-loss/limit fills can be
+	// simulated against intra-bar extremes rather than only the close.
+	ModeOHLC
+	// ModeTick explodes each TickBarEvent into its underlying ticks, in
+	// the order they were recorded; BarEvents without ticks fall back to
+	// ModeOHLC.
+	ModeTick
+)
+
+// Field identifies one of the OHLC fields of a bar.
+type Field int
+
+// The four fields a bar can reveal, in the order a backtest simulates
+// them becoming known.
+const (
+	FieldOpen Field = iota
+	FieldHigh
+	FieldLow
+	FieldClose
+)
+
+// String returns the name of the field, e.g. "open".
+func (f Field) String() string {
+	switch f {
+	case FieldOpen:
+		return "open"
+	case FieldHigh:
+		return "high"
+	case FieldLow:
+		return "low"
+	case FieldClose:
+		return "close"
+	default:
+		return "unknown"
+	}
+}
+
+// SubEvent is a single intra-bar observation produced by exploding a
+// BarEvent under ModeOHLC. Reading a field that has not yet been revealed
+// at this point in the bar panics, since doing so would leak look-ahead
+// information into the strategy.
+type SubEvent struct {
+	bar      BarEvent
+	field    Field
+	revealed [FieldClose + 1]bool
+}
+
+// Time returns the time of the parent bar. This satisfies the DataEvent
+// interface.
+func (s SubEvent) Time() time.Time {
+	return s.bar.Time()
+}
+
+// Symbol returns the symbol of the parent bar. This satisfies the
+// DataEvent interface.
+func (s SubEvent) Symbol() string {
+	return s.bar.Symbol()
+}
+
+// Open returns the bar's open price, if already revealed.
+func (s SubEvent) Open() float64 {
+	return s.value(FieldOpen)
+}
+
+// High returns the bar's high price, if already revealed.
+func (s SubEvent) High() float64 {
+	return s.value(FieldHigh)
+}
+
+// Low returns the bar's low price, if already revealed.
+func (s SubEvent) Low() float64 {
+	return s.value(FieldLow)
+}
+
+// Close returns the bar's close price, if already revealed.
+func (s SubEvent) Close() float64 {
+	return s.value(FieldClose)
+}
+
+// Field returns which field this sub-event reveals.
+func (s SubEvent) Field() Field {
+	return s.field
+}
+
+// value returns the bar's f field, panicking if f has not been revealed
+// yet at this sub-event's position in the bar.
+func (s SubEvent) value(f Field) float64 {
+	if !s.revealed[f] {
+		panic(fmt.Sprintf("backtest: look-ahead violation: %s for %s not yet revealed at the %s sub-event", f, s.bar.Symbol(), s.field))
+	}
+
+	switch f {
+	case FieldOpen:
+		return s.bar.Open()
+	case FieldHigh:
+		return s.bar.High()
+	case FieldLow:
+		return s.bar.Low()
+	case FieldClose:
+		return s.bar.Close()
+	default:
+		return 0
+	}
+}
+
 // Data is a basic data struct.
 type Data struct {
 	latest        map[string]DataEvent
 	list          map[string][]DataEvent
 	stream        []DataEvent
 	streamHistory []DataEvent
+	pending       []DataEvent
+
+	runs           []*eventRun
+	sourcePriority map[string]int
+	seq            int
+
+	blooms map[string]*symbolBloom
+
+	// Mode controls how bars are exploded into sub-events by Next.
+	Mode StreamMode
+}
+
+// SourcedEvent is implemented by events that carry the name of the
+// AddStream source they were merged from, so downstream handlers can
+// filter by origin.
+type SourcedEvent interface {
+	DataEvent
+	Source() string
+}
+
+// wrapSourced tags event with source, preserving whichever of
+// BarEvent/TickBarEvent it already implements. Always wrapping in a
+// plain DataEvent-embedding struct would strip a bar's Open/High/Low/
+// Close (and Ticks) methods, silently disabling ModeOHLC/ModeTick
+// exploding for every event routed through AddStream.
+func wrapSourced(event DataEvent, source string) DataEvent {
+	if tb, ok := event.(TickBarEvent); ok {
+		return sourcedTickBarEvent{TickBarEvent: tb, source: source}
+	}
+	if b, ok := event.(BarEvent); ok {
+		return sourcedBarEvent{BarEvent: b, source: source}
+	}
+	return sourcedEvent{DataEvent: event, source: source}
+}
+
+// sourcedEvent tags a plain event with the name of the source it was
+// merged from.
+type sourcedEvent struct {
+	DataEvent
+	source string
+}
+
+// Source returns the name the event's source was registered under. This
+// satisfies the SourcedEvent interface.
+func (s sourcedEvent) Source() string {
+	return s.source
+}
+
+// sourcedBarEvent is sourcedEvent for an event that also implements
+// BarEvent, so Open/High/Low/Close keep working after wrapping.
+type sourcedBarEvent struct {
+	BarEvent
+	source string
+}
+
+// Source returns the name the event's source was registered under.
+func (s sourcedBarEvent) Source() string {
+	return s.source
+}
+
+// sourcedTickBarEvent is sourcedEvent for an event that also implements
+// TickBarEvent, so Open/High/Low/Close/Ticks keep working after wrapping.
+type sourcedTickBarEvent struct {
+	TickBarEvent
+	source string
+}
+
+// Source returns the name the event's source was registered under.
+func (s sourcedTickBarEvent) Source() string {
+	return s.source
+}
+
+// eventRun is a single pre-sorted run of events contributed by one
+// AddStream call, ready to be k-way merged by SortStream.
+type eventRun struct {
+	source   string
+	priority int
+	events   []DataEvent
 }
 
 // Load loads data endpoints into a stream.
@@ -46,6 +260,9 @@ func (d *Data) Reset() error {
 	d.list = nil
 	d.stream = d.streamHistory
 	d.streamHistory = nil
+	d.pending = nil
+	d.runs = nil
+	d.blooms = nil
 	return nil
 }
 
@@ -59,16 +276,23 @@ func (d *Data) Stream() []DataEvent {
 	return d.stream
 }
 
-// Next returns the first element of the data stream,
-// deletes it from the stream and appends it to history.
+// Next returns the next data event in chronological order, deletes it from
+// the stream and appends it to history. Under ModeOHLC or ModeTick, a bar
+// is exploded into several sub-events and they are returned one at a time
+// across successive calls before the next bar is taken off the stream.
 func (d *Data) Next() (dh DataEvent, ok bool) {
-	// check for element in datastream
-	if len(d.stream) == 0 {
-		return dh, false
+	if len(d.pending) == 0 {
+		if len(d.stream) == 0 {
+			return dh, false
+		}
+
+		bar := d.stream[0]
+		d.stream = d.stream[1:]
+		d.pending = d.explode(bar)
 	}
 
-	dh = d.stream[0]
-	d.stream = d.stream[1:] // delete first element from stream
+	dh = d.pending[0]
+	d.pending = d.pending[1:]
 	d.streamHistory = append(d.streamHistory, dh)
 
 	// update list of current data events
@@ -79,6 +303,44 @@ func (d *Data) Next() (dh DataEvent, ok bool) {
 	return dh, true
 }
 
+// explode splits bar into its sub-events according to Mode. Under
+// ModeTick, a bar without ticks falls back to its OHLC sub-events; events
+// that are not BarEvents at all are delivered unexploded, at the bar
+// close.
+func (d *Data) explode(bar DataEvent) []DataEvent {
+	switch d.Mode {
+	case ModeTick:
+		if tb, ok := bar.(TickBarEvent); ok {
+			if ticks := tb.Ticks(); len(ticks) > 0 {
+				return ticks
+			}
+		}
+		if b, ok := bar.(BarEvent); ok {
+			return explodeOHLC(b)
+		}
+	case ModeOHLC:
+		if b, ok := bar.(BarEvent); ok {
+			return explodeOHLC(b)
+		}
+	}
+
+	return []DataEvent{bar}
+}
+
+// explodeOHLC returns bar's four sub-events in open, high, low, close
+// order, each one revealing progressively more of the bar.
+func explodeOHLC(bar BarEvent) []DataEvent {
+	order := [...]Field{FieldOpen, FieldHigh, FieldLow, FieldClose}
+
+	events := make([]DataEvent, 0, len(order))
+	var revealed [FieldClose + 1]bool
+	for _, f := range order {
+		revealed[f] = true
+		events = append(events, SubEvent{bar: bar, field: f, revealed: revealed})
+	}
+	return events
+}
+
 // History returns the historic data stream.
 func (d *Data) History() []DataEvent {
 	return d.streamHistory
@@ -94,21 +356,158 @@ func (d *Data) List(symbol string) []DataEvent {
 	return d.list[symbol]
 }
 
-// SortStream sorts the dataStream.
+// RegisterSource assigns priority to source. When two events added via
+// AddStream share a timestamp, the event from the higher-priority source
+// is delivered first by SortStream — e.g. giving trades priority over
+// quotes over bars. Sources that are never registered default to
+// priority 0.
+func (d *Data) RegisterSource(source string, priority int) {
+	if d.sourcePriority == nil {
+		d.sourcePriority = make(map[string]int)
+	}
+	d.sourcePriority[source] = priority
+}
+
+// AddStream adds events, a run already sorted in chronological order, as
+// a named source to be merged into the stream by the next call to
+// SortStream. Unlike SetStream, repeated calls accumulate runs instead of
+// replacing the stream outright, so events from several sources (or
+// several symbols loaded separately) can be merged without resorting
+// everything seen so far.
+func (d *Data) AddStream(source string, events []DataEvent) {
+	d.runs = append(d.runs, &eventRun{
+		source:   source,
+		priority: d.sourcePriority[source],
+		events:   events,
+	})
+}
+
+// SortStream merges every run added via AddStream — plus, for backward
+// compatibility, any events already set directly via SetStream — into a
+// single chronological stream. It performs a k-way merge with a min-heap
+// keyed on (time, source priority, symbol, arrival order), which is
+// O(total·log N) for N runs rather than resorting the full stream on
+// every addition, and breaks ties deterministically and reproducibly
+// across runs.
+//
+// Events set via SetStream are not assumed to already be sorted — unlike
+// AddStream runs, which callers contract to hand over pre-sorted — since
+// historically they were often assembled by concatenating several
+// per-symbol runs and handed to the old full-slice SortStream. They are
+// sorted once here before being merged in as a single run.
 func (d *Data) SortStream() {
-	sort.Slice(d.stream, func(i, j int) bool {
-		b1 := d.stream[i]
-		b2 := d.stream[j]
+	runs := d.runs
+	if len(d.stream) > 0 {
+		legacy := append([]DataEvent(nil), d.stream...)
+		sortEvents(legacy)
+		runs = append(runs, &eventRun{events: legacy})
+	}
+	if len(runs) == 0 {
+		return
+	}
+
+	h := make(mergeHeap, 0, len(runs))
+	for i, r := range runs {
+		if len(r.events) == 0 {
+			continue
+		}
+		h = append(h, d.newMergeItem(i, 0, r))
+	}
+	heap.Init(&h)
 
-		// if date is equal sort by symbol
-		if b1.Time().Equal(b2.Time()) {
-			return b1.Symbol() < b2.Symbol()
+	var merged []DataEvent
+	for h.Len() > 0 {
+		item := heap.Pop(&h).(*mergeItem)
+		run := runs[item.runIdx]
+
+		event := run.events[item.index]
+		if run.source != "" {
+			event = wrapSourced(event, run.source)
+		}
+		merged = append(merged, event)
+
+		if next := item.index + 1; next < len(run.events) {
+			heap.Push(&h, d.newMergeItem(item.runIdx, next, run))
+		}
+	}
+
+	d.stream = merged
+	d.runs = nil
+}
+
+// sortEvents sorts events in place by time, breaking ties by symbol.
+func sortEvents(events []DataEvent) {
+	sort.Slice(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if a.Time().Equal(b.Time()) {
+			return a.Symbol() < b.Symbol()
 		}
-		// else sort by date
-		return b1.Time().Before(b2.Time())
+		return a.Time().Before(b.Time())
 	})
 }
 
+// newMergeItem builds the heap entry for run's event at index, stamping
+// it with the next arrival-order sequence number so ties are broken
+// deterministically.
+func (d *Data) newMergeItem(runIdx, index int, run *eventRun) *mergeItem {
+	d.seq++
+	e := run.events[index]
+	return &mergeItem{
+		runIdx:   runIdx,
+		index:    index,
+		time:     e.Time(),
+		priority: run.priority,
+		symbol:   e.Symbol(),
+		seq:      d.seq,
+	}
+}
+
+// mergeItem is one candidate event in the k-way merge heap: the next
+// unmerged event of a single run, plus the fields SortStream orders on.
+type mergeItem struct {
+	runIdx   int
+	index    int
+	time     time.Time
+	priority int
+	symbol   string
+	seq      int
+}
+
+// mergeHeap is a container/heap.Interface over mergeItems, ordered by
+// (time, source priority descending, symbol, arrival order).
+type mergeHeap []*mergeItem
+
+func (h mergeHeap) Len() int { return len(h) }
+
+func (h mergeHeap) Less(i, j int) bool {
+	a, b := h[i], h[j]
+
+	if !a.time.Equal(b.time) {
+		return a.time.Before(b.time)
+	}
+	if a.priority != b.priority {
+		return a.priority > b.priority
+	}
+	if a.symbol != b.symbol {
+		return a.symbol < b.symbol
+	}
+	return a.seq < b.seq
+}
+
+func (h mergeHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *mergeHeap) Push(x interface{}) {
+	*h = append(*h, x.(*mergeItem))
+}
+
+func (h *mergeHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
 // updateLatest puts the last current data event to the current list.
 func (d *Data) updateLatest(event DataEvent) {
 	// check for nil map, else initialise the map
@@ -127,4 +526,6 @@ func (d *Data) updateList(event DataEvent) {
 	}
 
 	d.list[event.Symbol()] = append(d.list[event.Symbol()], event)
+
+	d.recordBloom(event)
 }
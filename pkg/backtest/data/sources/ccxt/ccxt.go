@@ -0,0 +1,99 @@
+// Package ccxt is a generic REST OHLCV loader for venues without a
+// dedicated backend, registered with the sources registry under the
+// "ccxt" scheme.
+package ccxt
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/finbotsdev/gobacktest/pkg/backtest"
+	"github.com/finbotsdev/gobacktest/pkg/backtest/data/sources"
+	"github.com/finbotsdev/gobacktest/pkg/backtest/kline"
+)
+
+func init() {
+	sources.RegisterLoader("ccxt", sources.LoaderFactoryFunc(newLoader))
+}
+
+// Loader fetches OHLCV candles from any REST endpoint that returns
+// ccxt's standard fetchOHLCV array shape — [[ms, open, high, low,
+// close, volume], ...] — addressed by a
+// ccxt://_?endpoint=<url-encoded REST URL>&symbol=BTC/USDT&interval=1h
+// URI. This is the escape hatch for venues without a dedicated backend
+// like binance or coinbase.
+type Loader struct {
+	endpoint string
+	symbol   string
+	interval kline.Interval
+	client   *http.Client
+}
+
+func newLoader(u *url.URL) (sources.Loader, error) {
+	q := u.Query()
+
+	endpoint := q.Get("endpoint")
+	if endpoint == "" {
+		return nil, fmt.Errorf("ccxt: uri %q missing required endpoint query parameter", u)
+	}
+
+	symbol := q.Get("symbol")
+	if symbol == "" {
+		return nil, fmt.Errorf("ccxt: uri %q missing required symbol query parameter", u)
+	}
+
+	interval, err := parseInterval(q.Get("interval"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Loader{endpoint: endpoint, symbol: symbol, interval: interval, client: http.DefaultClient}, nil
+}
+
+func parseInterval(s string) (kline.Interval, error) {
+	switch s {
+	case "", "1h":
+		return kline.Interval1Hour, nil
+	case "1m":
+		return kline.Interval1Min, nil
+	case "5m":
+		return kline.Interval5Min, nil
+	case "1d":
+		return kline.Interval1Day, nil
+	default:
+		return 0, fmt.Errorf("ccxt: unsupported interval %q", s)
+	}
+}
+
+// ohlcvRow is one row of ccxt's standard fetchOHLCV shape: [timestamp
+// (ms), open, high, low, close, volume].
+type ohlcvRow [6]float64
+
+// Load fetches l.endpoint and returns its rows as kline.Candle events.
+func (l *Loader) Load(uri string) ([]backtest.DataEvent, error) {
+	resp, err := l.client.Get(l.endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("ccxt: fetch %q: %w", uri, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ccxt: fetch %q: unexpected status %s", uri, resp.Status)
+	}
+
+	var rows []ohlcvRow
+	if err := json.NewDecoder(resp.Body).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("ccxt: decode %q: %w", uri, err)
+	}
+
+	events := make([]backtest.DataEvent, 0, len(rows))
+	for _, row := range rows {
+		ts := time.UnixMilli(int64(row[0])).UTC()
+		events = append(events, kline.NewCandle(l.symbol, l.interval, ts, row[1], row[2], row[3], row[4], row[5]))
+	}
+
+	return events, nil
+}
@@ -0,0 +1,137 @@
+// Package csv loads OHLCV candles from a local CSV file, registered with
+// the sources registry under the "csv" scheme.
+package csv
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/finbotsdev/gobacktest/pkg/backtest"
+	"github.com/finbotsdev/gobacktest/pkg/backtest/data/sources"
+	"github.com/finbotsdev/gobacktest/pkg/backtest/kline"
+)
+
+func init() {
+	sources.RegisterLoader("csv", sources.LoaderFactoryFunc(newLoader))
+}
+
+// Loader reads OHLCV candles from a local CSV file, addressed by a
+// csv:///path/to/file.csv?symbol=AAPL&interval=1d&tz=UTC URI. Rows are
+// expected in the layout timestamp,open,high,low,close,volume, with an
+// optional header row.
+type Loader struct {
+	path     string
+	symbol   string
+	interval kline.Interval
+	loc      *time.Location
+}
+
+func newLoader(u *url.URL) (sources.Loader, error) {
+	symbol := u.Query().Get("symbol")
+	if symbol == "" {
+		return nil, fmt.Errorf("csv: uri %q missing required symbol query parameter", u)
+	}
+
+	interval, err := parseInterval(u.Query().Get("interval"))
+	if err != nil {
+		return nil, err
+	}
+
+	tz := u.Query().Get("tz")
+	if tz == "" {
+		tz = "UTC"
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("csv: load timezone %q: %w", tz, err)
+	}
+
+	path := u.Path
+	if path == "" {
+		path = u.Opaque
+	}
+
+	return &Loader{path: path, symbol: symbol, interval: interval, loc: loc}, nil
+}
+
+func parseInterval(s string) (kline.Interval, error) {
+	switch s {
+	case "", "1d":
+		return kline.Interval1Day, nil
+	case "1h":
+		return kline.Interval1Hour, nil
+	case "5m":
+		return kline.Interval5Min, nil
+	case "1m":
+		return kline.Interval1Min, nil
+	default:
+		return 0, fmt.Errorf("csv: unsupported interval %q", s)
+	}
+}
+
+// Load reads every row of the file at l.path and returns one
+// kline.Candle per row. uri is the URI Load was dispatched from and is
+// only used to annotate errors.
+func (l *Loader) Load(uri string) ([]backtest.DataEvent, error) {
+	f, err := os.Open(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("csv: open %q: %w", uri, err)
+	}
+	defer f.Close()
+
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("csv: read %q: %w", uri, err)
+	}
+
+	if len(rows) > 0 && !looksNumeric(rows[0]) {
+		rows = rows[1:] // drop header
+	}
+
+	events := make([]backtest.DataEvent, 0, len(rows))
+	for i, row := range rows {
+		if len(row) < 6 {
+			return nil, fmt.Errorf("csv: %q: row %d has %d columns, want 6", uri, i, len(row))
+		}
+
+		ts, err := time.ParseInLocation(time.RFC3339, row[0], l.loc)
+		if err != nil {
+			return nil, fmt.Errorf("csv: %q: row %d: parse timestamp: %w", uri, i, err)
+		}
+
+		values, err := parseFloats(row[1:6])
+		if err != nil {
+			return nil, fmt.Errorf("csv: %q: row %d: %w", uri, i, err)
+		}
+
+		events = append(events, kline.NewCandle(l.symbol, l.interval, ts, values[0], values[1], values[2], values[3], values[4]))
+	}
+
+	return events, nil
+}
+
+// looksNumeric reports whether row's price columns parse as numbers,
+// used to tell a header row apart from a data row.
+func looksNumeric(row []string) bool {
+	if len(row) < 2 {
+		return false
+	}
+	_, err := strconv.ParseFloat(row[1], 64)
+	return err == nil
+}
+
+func parseFloats(cols []string) ([5]float64, error) {
+	var out [5]float64
+	for i, c := range cols {
+		v, err := strconv.ParseFloat(c, 64)
+		if err != nil {
+			return out, fmt.Errorf("parse column %d (%q): %w", i, c, err)
+		}
+		out[i] = v
+	}
+	return out, nil
+}
@@ -0,0 +1,16 @@
+// Package parquet is a placeholder for a columnar Parquet file backend,
+// addressed by a parquet:///path/to/file.parquet?symbol=AAPL&interval=1d
+// URI.
+//
+// Parquet support is out of scope for this change: decoding Parquet's
+// column layout needs a dedicated reader dependency (e.g.
+// github.com/apache/arrow/go/parquet) that isn't vendored here, and
+// pulling one in is a bigger decision than this backlog item covers.
+// This package therefore registers no "parquet" scheme with the sources
+// registry — a registered scheme that resolves but can never load is a
+// worse failure mode for LoadAll than a clear "no loader registered"
+// error — and should be treated as unimplemented, not as a delivered
+// backend. Implement Load and call
+// sources.RegisterLoader("parquet", sources.LoaderFactoryFunc(...)) in
+// init when a decoder dependency is available.
+package parquet
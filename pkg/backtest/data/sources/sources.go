@@ -0,0 +1,106 @@
+// Package sources is a registry of venue- and format-specific data
+// loaders. Each loader is addressed by the scheme of a URI/DSN, e.g.
+// csv:///path/file.csv?symbol=AAPL&tz=UTC or binance://BTCUSDT?interval=1h&start=...,
+// so callers can assemble a backtest.Data stream from any mix of sources
+// without the core backtest package knowing about any of them.
+package sources
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+
+	"github.com/finbotsdev/gobacktest/pkg/backtest"
+)
+
+// Loader loads DataEvents from a single source URI.
+type Loader interface {
+	Load(uri string) ([]backtest.DataEvent, error)
+}
+
+// LoaderFactory constructs a Loader for a parsed source URI. Implementations
+// register themselves against a URI scheme via RegisterLoader, typically
+// from an init function in their own package.
+type LoaderFactory interface {
+	New(u *url.URL) (Loader, error)
+}
+
+// LoaderFactoryFunc adapts a plain function to a LoaderFactory.
+type LoaderFactoryFunc func(u *url.URL) (Loader, error)
+
+// New calls f.
+func (f LoaderFactoryFunc) New(u *url.URL) (Loader, error) {
+	return f(u)
+}
+
+var (
+	mu       sync.RWMutex
+	registry = make(map[string]LoaderFactory)
+)
+
+// RegisterLoader registers factory as the LoaderFactory for scheme. It
+// panics if scheme is already registered or factory is nil, mirroring
+// driver registration in database/sql.
+func RegisterLoader(scheme string, factory LoaderFactory) {
+	if factory == nil {
+		panic("sources: RegisterLoader factory is nil")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, dup := registry[scheme]; dup {
+		panic("sources: RegisterLoader called twice for scheme " + scheme)
+	}
+	registry[scheme] = factory
+}
+
+// Load parses uri, dispatches to the LoaderFactory registered for its
+// scheme, and loads its events.
+func Load(uri string) ([]backtest.DataEvent, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("sources: parse %q: %w", uri, err)
+	}
+
+	mu.RLock()
+	factory, ok := registry[u.Scheme]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("sources: no loader registered for scheme %q", u.Scheme)
+	}
+
+	loader, err := factory.New(u)
+	if err != nil {
+		return nil, fmt.Errorf("sources: create loader for %q: %w", uri, err)
+	}
+
+	events, err := loader.Load(uri)
+	if err != nil {
+		return nil, fmt.Errorf("sources: load %q: %w", uri, err)
+	}
+	return events, nil
+}
+
+// LoadAll loads every uri and merges the results into a single,
+// time-sorted stream ready for backtest.Data.SetStream.
+func LoadAll(uris []string) ([]backtest.DataEvent, error) {
+	var merged []backtest.DataEvent
+	for _, uri := range uris {
+		events, err := Load(uri)
+		if err != nil {
+			return nil, err
+		}
+		merged = append(merged, events...)
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		if merged[i].Time().Equal(merged[j].Time()) {
+			return merged[i].Symbol() < merged[j].Symbol()
+		}
+		return merged[i].Time().Before(merged[j].Time())
+	})
+
+	return merged, nil
+}
@@ -0,0 +1,75 @@
+package backtest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/finbotsdev/gobacktest/pkg/backtest"
+	"github.com/finbotsdev/gobacktest/pkg/backtest/kline"
+)
+
+func TestDataNextExplodesOHLCInOrder(t *testing.T) {
+	d := &backtest.Data{Mode: backtest.ModeOHLC}
+	bar := kline.NewCandle("AAA", kline.Interval1Hour, time.Now(), 1, 4, 0, 3, 10)
+	d.SetStream([]backtest.DataEvent{bar})
+
+	want := []backtest.Field{backtest.FieldOpen, backtest.FieldHigh, backtest.FieldLow, backtest.FieldClose}
+	for i, field := range want {
+		event, ok := d.Next()
+		if !ok {
+			t.Fatalf("Next() sub-event %d: ok = false, want true", i)
+		}
+		sub, ok := event.(backtest.SubEvent)
+		if !ok {
+			t.Fatalf("Next() sub-event %d: type = %T, want backtest.SubEvent", i, event)
+		}
+		if sub.Field() != field {
+			t.Errorf("sub-event %d: Field() = %v, want %v", i, sub.Field(), field)
+		}
+	}
+
+	if _, ok := d.Next(); ok {
+		t.Fatal("Next() after 4 sub-events: ok = true, want false (stream exhausted)")
+	}
+}
+
+func TestSubEventPanicsOnUnrevealedField(t *testing.T) {
+	d := &backtest.Data{Mode: backtest.ModeOHLC}
+	bar := kline.NewCandle("AAA", kline.Interval1Hour, time.Now(), 1, 4, 0, 3, 10)
+	d.SetStream([]backtest.DataEvent{bar})
+
+	event, _ := d.Next() // the open sub-event; high/low/close are not revealed yet
+	sub := event.(backtest.SubEvent)
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Close() on the open sub-event did not panic, want a look-ahead violation panic")
+		}
+	}()
+	_ = sub.Close()
+}
+
+func TestSubEventRevealsFieldsProgressively(t *testing.T) {
+	d := &backtest.Data{Mode: backtest.ModeOHLC}
+	bar := kline.NewCandle("AAA", kline.Interval1Hour, time.Now(), 1, 4, 0, 3, 10)
+	d.SetStream([]backtest.DataEvent{bar})
+
+	for i := 0; i < 2; i++ { // consume open and high, leaving low next
+		if _, ok := d.Next(); !ok {
+			t.Fatalf("Next() call %d: ok = false, want true", i)
+		}
+	}
+	event, _ := d.Next() // the low sub-event
+
+	sub := event.(backtest.SubEvent)
+
+	if got := sub.Open(); got != 1 {
+		t.Errorf("Open() = %v, want 1", got)
+	}
+	if got := sub.High(); got != 4 {
+		t.Errorf("High() = %v, want 4", got)
+	}
+	if got := sub.Low(); got != 0 {
+		t.Errorf("Low() = %v, want 0", got)
+	}
+}
@@ -0,0 +1,461 @@
+// Package kline provides a backtest.DataHandler implementation specialised
+// for OHLCV bar data. It keeps each symbol's candles at their native
+// interval and can resample them on the fly into a coarser interval, so a
+// single backtest can mix e.g. a 1m signal source with a 1h execution
+// timeframe. This mirrors the split between the generic backtest/data
+// package and a kline-specific implementation.
+package kline
+
+import (
+	"sort"
+	"time"
+
+	"github.com/finbotsdev/gobacktest/pkg/backtest"
+)
+
+// Interval is the bar period of a Candle, either native to the loaded data
+// or the target of a resampling operation.
+type Interval time.Duration
+
+// Supported native intervals.
+const (
+	Interval1Min Interval = Interval(time.Minute)
+	Interval5Min Interval = Interval(5 * time.Minute)
+	Interval1Hour Interval = Interval(time.Hour)
+	Interval1Day Interval = Interval(24 * time.Hour)
+)
+
+// String returns the common shorthand for the interval, e.g. "1h".
+func (i Interval) String() string {
+	switch i {
+	case Interval1Min:
+		return "1m"
+	case Interval5Min:
+		return "5m"
+	case Interval1Hour:
+		return "1h"
+	case Interval1Day:
+		return "1d"
+	default:
+		return time.Duration(i).String()
+	}
+}
+
+// Candle is an OHLCV data event for a single symbol at a given interval.
+type Candle struct {
+	timestamp time.Time
+	symbol    string
+	interval  Interval
+	open      float64
+	high      float64
+	low       float64
+	close     float64
+	volume    float64
+}
+
+// Time returns the close time of the candle. This satisfies the
+// backtest.DataEvent interface.
+func (c Candle) Time() time.Time {
+	return c.timestamp
+}
+
+// Symbol returns the symbol the candle belongs to. This satisfies the
+// backtest.DataEvent interface.
+func (c Candle) Symbol() string {
+	return c.symbol
+}
+
+// Interval returns the bar period the candle was built at.
+func (c Candle) Interval() Interval {
+	return c.interval
+}
+
+// Open returns the candle's opening price. This satisfies the
+// backtest.BarEvent interface.
+func (c Candle) Open() float64 {
+	return c.open
+}
+
+// High returns the candle's highest traded price. This satisfies the
+// backtest.BarEvent interface.
+func (c Candle) High() float64 {
+	return c.high
+}
+
+// Low returns the candle's lowest traded price. This satisfies the
+// backtest.BarEvent interface.
+func (c Candle) Low() float64 {
+	return c.low
+}
+
+// Close returns the candle's closing price. This satisfies the
+// backtest.BarEvent interface.
+func (c Candle) Close() float64 {
+	return c.close
+}
+
+// Volume returns the candle's traded volume.
+func (c Candle) Volume() float64 {
+	return c.volume
+}
+
+// NewCandle creates a Candle for symbol at the given interval.
+func NewCandle(symbol string, interval Interval, timestamp time.Time, open, high, low, close, volume float64) Candle {
+	return Candle{
+		timestamp: timestamp,
+		symbol:    symbol,
+		interval:  interval,
+		open:      open,
+		high:      high,
+		low:       low,
+		close:     close,
+		volume:    volume,
+	}
+}
+
+// Handler is a DataHandler implementation specialised for OHLCV candles.
+// It loads symbols at one or more native intervals and merges them into a
+// single chronological stream, while also maintaining resampled series for
+// any coarser interval a strategy asks for via Resample.
+type Handler struct {
+	stream        []Candle
+	streamHistory []Candle
+
+	latest map[string]map[Interval]Candle
+	list   map[string]map[Interval][]Candle
+
+	targets    []Interval
+	resamplers map[string]map[Interval]*resampler
+}
+
+// NewHandler creates a new, empty kline data handler.
+func NewHandler() *Handler {
+	return &Handler{}
+}
+
+// Load satisfies the backtest.DataLoader interface. The kline handler does
+// not know how to fetch candles itself; use SetCandles, or one of the
+// backtest/data/sources loaders, to populate the stream.
+func (h *Handler) Load(s []string) error {
+	return nil
+}
+
+// SetCandles replaces the underlying candle stream with candles, which may
+// span multiple symbols and native intervals, and sorts it into strict
+// chronological order.
+func (h *Handler) SetCandles(candles []Candle) {
+	h.stream = candles
+	h.sortStream()
+}
+
+// Resample registers target as an interval to maintain alongside the
+// native data. Once registered, LatestInterval and ListInterval return
+// bars resampled up to target as the stream is consumed via Next.
+func (h *Handler) Resample(target Interval) {
+	for _, existing := range h.targets {
+		if existing == target {
+			return
+		}
+	}
+	h.targets = append(h.targets, target)
+}
+
+// sortStream orders the candle stream by time, breaking ties by symbol so
+// ordering across symbols and intervals is deterministic.
+func (h *Handler) sortStream() {
+	sort.Slice(h.stream, func(i, j int) bool {
+		a, b := h.stream[i], h.stream[j]
+		if a.timestamp.Equal(b.timestamp) {
+			return a.symbol < b.symbol
+		}
+		return a.timestamp.Before(b.timestamp)
+	})
+}
+
+// Next returns the next candle in chronological order across all
+// symbols and native intervals, removes it from the stream, appends it to
+// history and feeds it through any registered resamplers. Once the
+// native stream is exhausted, Next flushes every resampler's trailing,
+// still-forming bucket so it becomes visible via LatestInterval/
+// ListInterval rather than being silently dropped.
+func (h *Handler) Next() (backtest.DataEvent, bool) {
+	if len(h.stream) == 0 {
+		h.Flush()
+		return nil, false
+	}
+
+	c := h.stream[0]
+	h.stream = h.stream[1:]
+	h.streamHistory = append(h.streamHistory, c)
+
+	h.updateLatest(c)
+	h.updateList(c)
+	h.updateResampled(c)
+
+	return c, true
+}
+
+// Stream returns the remaining native-interval candle stream.
+func (h *Handler) Stream() []backtest.DataEvent {
+	return toDataEvents(h.stream)
+}
+
+// History returns the consumed native-interval candle stream.
+func (h *Handler) History() []backtest.DataEvent {
+	return toDataEvents(h.streamHistory)
+}
+
+// Latest returns the last native-interval candle seen for symbol.
+func (h *Handler) Latest(symbol string) backtest.DataEvent {
+	return h.LatestInterval(symbol, 0)
+}
+
+// List returns all native-interval candles seen so far for symbol.
+func (h *Handler) List(symbol string) []backtest.DataEvent {
+	return h.ListInterval(symbol, 0)
+}
+
+// LatestInterval returns the last candle seen for symbol at interval. An
+// interval of 0 selects the candle's own native interval.
+func (h *Handler) LatestInterval(symbol string, interval Interval) backtest.DataEvent {
+	byInterval, ok := h.latest[symbol]
+	if !ok {
+		return nil
+	}
+
+	if interval == 0 {
+		return h.latestNative(byInterval)
+	}
+
+	c, ok := byInterval[interval]
+	if !ok {
+		return nil
+	}
+	return c
+}
+
+// ListInterval returns all candles seen so far for symbol at interval. An
+// interval of 0 selects each candle's own native interval.
+func (h *Handler) ListInterval(symbol string, interval Interval) []backtest.DataEvent {
+	byInterval, ok := h.list[symbol]
+	if !ok {
+		return nil
+	}
+
+	if interval != 0 {
+		return toDataEvents(byInterval[interval])
+	}
+
+	var native []Candle
+	for iv, candles := range byInterval {
+		if !h.isTarget(iv) {
+			native = append(native, candles...)
+		}
+	}
+	sort.Slice(native, func(i, j int) bool { return native[i].timestamp.Before(native[j].timestamp) })
+	return toDataEvents(native)
+}
+
+// Reset implements the backtest.Reseter interface and rewinds the handler
+// to its loaded, unconsumed state.
+func (h *Handler) Reset() error {
+	h.latest = nil
+	h.list = nil
+	h.stream = append(h.streamHistory, h.stream...)
+	h.streamHistory = nil
+	h.resamplers = nil
+	h.sortStream()
+	return nil
+}
+
+// isTarget reports whether interval is a registered resampling target
+// rather than one of the native intervals present in the loaded data.
+func (h *Handler) isTarget(interval Interval) bool {
+	for _, target := range h.targets {
+		if target == interval {
+			return true
+		}
+	}
+	return false
+}
+
+// updateLatest records c as the latest candle for its symbol at its own
+// native interval.
+func (h *Handler) updateLatest(c Candle) {
+	if h.latest == nil {
+		h.latest = make(map[string]map[Interval]Candle)
+	}
+	if h.latest[c.symbol] == nil {
+		h.latest[c.symbol] = make(map[Interval]Candle)
+	}
+	h.latest[c.symbol][c.interval] = c
+}
+
+// updateList appends c to the candle list for its symbol at its own
+// native interval.
+func (h *Handler) updateList(c Candle) {
+	if h.list == nil {
+		h.list = make(map[string]map[Interval][]Candle)
+	}
+	if h.list[c.symbol] == nil {
+		h.list[c.symbol] = make(map[Interval][]Candle)
+	}
+	h.list[c.symbol][c.interval] = append(h.list[c.symbol][c.interval], c)
+}
+
+// updateResampled feeds c through every registered target interval's
+// resampler for its symbol, recording a new bar each time a bucket
+// completes.
+func (h *Handler) updateResampled(c Candle) {
+	for _, target := range h.targets {
+		if target <= c.interval {
+			continue
+		}
+
+		if h.resamplers == nil {
+			h.resamplers = make(map[string]map[Interval]*resampler)
+		}
+		if h.resamplers[c.symbol] == nil {
+			h.resamplers[c.symbol] = make(map[Interval]*resampler)
+		}
+		r, ok := h.resamplers[c.symbol][target]
+		if !ok {
+			r = &resampler{interval: target}
+			h.resamplers[c.symbol][target] = r
+		}
+
+		if bar, done := r.add(c); done {
+			h.updateLatest(bar)
+			h.updateList(bar)
+		}
+	}
+}
+
+// Flush finalises every resampler's in-progress bucket into a bar, so the
+// trailing, still-forming resampled bar for each symbol/target interval
+// becomes visible via LatestInterval/ListInterval. Without it, a bucket
+// is only emitted once a later candle starts the next one, so the final
+// bucket of any stream would otherwise never be delivered. Next calls
+// Flush automatically once the native stream is exhausted; call it
+// directly to see a resampled bar mid-stream, e.g. before it would
+// otherwise close. A mid-stream Flush closes the bucket: a later candle
+// in the same window starts a new bar for that window rather than
+// extending the flushed one. Flushing twice without new candles in
+// between is a no-op.
+func (h *Handler) Flush() {
+	for _, bySymbol := range h.resamplers {
+		for _, r := range bySymbol {
+			if bar, ok := r.flush(); ok {
+				h.updateLatest(bar)
+				h.updateList(bar)
+			}
+		}
+	}
+}
+
+// latestNative returns the candle with the most recent timestamp among
+// the native-interval entries of byInterval, skipping resampled target
+// intervals the same way ListInterval's native case does — otherwise a
+// resampled bar sharing the latest native timestamp could be returned in
+// its place, nondeterministically depending on map iteration order.
+func (h *Handler) latestNative(byInterval map[Interval]Candle) backtest.DataEvent {
+	var latest Candle
+	var found bool
+	for iv, c := range byInterval {
+		if h.isTarget(iv) {
+			continue
+		}
+		if !found || c.timestamp.After(latest.timestamp) {
+			latest = c
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+	return latest
+}
+
+// toDataEvents adapts a slice of Candle to the backtest.DataEvent
+// interface expected by DataStreamer.
+func toDataEvents(candles []Candle) []backtest.DataEvent {
+	if candles == nil {
+		return nil
+	}
+	events := make([]backtest.DataEvent, len(candles))
+	for i, c := range candles {
+		events[i] = c
+	}
+	return events
+}
+
+// resampler aggregates a stream of native-interval candles for one symbol
+// into bars of a coarser target interval, bucketed on interval-aligned
+// window boundaries.
+type resampler struct {
+	interval Interval
+	bucket   time.Time
+	working  Candle
+	open     bool
+}
+
+// add folds c into the in-progress bar. It returns the previous bar and
+// true once c starts a new bucket, signalling that the previous bar is
+// complete.
+func (r *resampler) add(c Candle) (Candle, bool) {
+	bucket := c.timestamp.Truncate(time.Duration(r.interval))
+
+	if !r.open {
+		r.start(bucket, c)
+		return Candle{}, false
+	}
+
+	if bucket.Equal(r.bucket) {
+		r.merge(c)
+		return Candle{}, false
+	}
+
+	done := r.working
+	r.start(bucket, c)
+	return done, true
+}
+
+// start begins a new bucket seeded with c.
+func (r *resampler) start(bucket time.Time, c Candle) {
+	r.bucket = bucket
+	r.working = Candle{
+		timestamp: c.timestamp,
+		symbol:    c.symbol,
+		interval:  r.interval,
+		open:      c.open,
+		high:      c.high,
+		low:       c.low,
+		close:     c.close,
+		volume:    c.volume,
+	}
+	r.open = true
+}
+
+// merge folds c into the current bucket.
+func (r *resampler) merge(c Candle) {
+	if c.high > r.working.high {
+		r.working.high = c.high
+	}
+	if c.low < r.working.low {
+		r.working.low = c.low
+	}
+	r.working.close = c.close
+	r.working.volume += c.volume
+	r.working.timestamp = c.timestamp
+}
+
+// flush finalises the in-progress bucket, if any, and marks it closed so
+// a later flush with no new candles in between is a no-op.
+func (r *resampler) flush() (Candle, bool) {
+	if !r.open {
+		return Candle{}, false
+	}
+	bar := r.working
+	r.open = false
+	return bar, true
+}
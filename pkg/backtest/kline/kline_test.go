@@ -0,0 +1,117 @@
+package kline
+
+import (
+	"testing"
+	"time"
+)
+
+func minute(i int) time.Time {
+	return time.Date(2024, 1, 1, 0, i, 0, 0, time.UTC)
+}
+
+func TestHandlerResampleMergesIntoBucket(t *testing.T) {
+	h := NewHandler()
+	h.Resample(Interval1Hour)
+
+	h.SetCandles([]Candle{
+		NewCandle("AAA", Interval1Min, minute(0), 10, 12, 9, 11, 100),
+		NewCandle("AAA", Interval1Min, minute(1), 11, 13, 10, 12, 100),
+		NewCandle("AAA", Interval1Min, minute(2), 12, 14, 11, 13, 100),
+	})
+
+	for {
+		if _, ok := h.Next(); !ok {
+			break
+		}
+	}
+
+	got := h.LatestInterval("AAA", Interval1Hour)
+	if got == nil {
+		t.Fatal("LatestInterval(1h) = nil, want a flushed resampled bar")
+	}
+	bar := got.(Candle)
+
+	if bar.Open() != 10 {
+		t.Errorf("Open() = %v, want 10 (first candle's open)", bar.Open())
+	}
+	if bar.High() != 14 {
+		t.Errorf("High() = %v, want 14 (max high across the bucket)", bar.High())
+	}
+	if bar.Low() != 9 {
+		t.Errorf("Low() = %v, want 9 (min low across the bucket)", bar.Low())
+	}
+	if bar.Close() != 13 {
+		t.Errorf("Close() = %v, want 13 (last candle's close)", bar.Close())
+	}
+	if bar.Volume() != 300 {
+		t.Errorf("Volume() = %v, want 300 (sum of the bucket)", bar.Volume())
+	}
+}
+
+func TestHandlerResampleFlushesTrailingBucketOnExhaustion(t *testing.T) {
+	h := NewHandler()
+	h.Resample(Interval1Hour)
+
+	h.SetCandles([]Candle{
+		NewCandle("AAA", Interval1Min, minute(0), 10, 10, 10, 10, 1),
+	})
+
+	if got := h.ListInterval("AAA", Interval1Hour); got != nil {
+		t.Fatalf("ListInterval(1h) before consuming the stream = %v, want nil", got)
+	}
+
+	for {
+		if _, ok := h.Next(); !ok {
+			break
+		}
+	}
+
+	if got := h.ListInterval("AAA", Interval1Hour); len(got) != 1 {
+		t.Fatalf("ListInterval(1h) after stream exhaustion = %d bars, want 1 (the flushed trailing bucket)", len(got))
+	}
+}
+
+func TestHandlerResampleStartsNewBucketOnBoundary(t *testing.T) {
+	h := NewHandler()
+	h.Resample(Interval1Hour)
+
+	hour := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)
+	h.SetCandles([]Candle{
+		NewCandle("AAA", Interval1Min, minute(59), 1, 1, 1, 1, 1),
+		NewCandle("AAA", Interval1Min, hour, 2, 2, 2, 2, 1),
+	})
+
+	for {
+		if _, ok := h.Next(); !ok {
+			break
+		}
+	}
+
+	bars := h.ListInterval("AAA", Interval1Hour)
+	if len(bars) != 2 {
+		t.Fatalf("ListInterval(1h) = %d bars, want 2 (one closed by the boundary, one flushed trailing)", len(bars))
+	}
+}
+
+func TestHandlerLatestSkipsResampledTargets(t *testing.T) {
+	h := NewHandler()
+	h.Resample(Interval1Hour)
+
+	h.SetCandles([]Candle{
+		NewCandle("AAA", Interval1Min, minute(0), 1, 1, 1, 1, 1),
+	})
+
+	for {
+		if _, ok := h.Next(); !ok {
+			break
+		}
+	}
+
+	got := h.Latest("AAA")
+	if got == nil {
+		t.Fatal("Latest(\"AAA\") = nil")
+	}
+	if bar := got.(Candle); bar.Interval() != Interval1Min {
+		t.Errorf("Latest(\"AAA\").Interval() = %v, want Interval1Min (the resampled 1h target must not be returned)", bar.Interval())
+	}
+}
@@ -0,0 +1,76 @@
+package backtest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/finbotsdev/gobacktest/pkg/backtest"
+	"github.com/finbotsdev/gobacktest/pkg/backtest/kline"
+)
+
+func candle(symbol string, t time.Time) kline.Candle {
+	return kline.NewCandle(symbol, kline.Interval1Hour, t, 1, 1, 1, 1, 1)
+}
+
+func TestSortStreamOrdersRunsByTime(t *testing.T) {
+	d := &backtest.Data{}
+	base := time.Now()
+
+	d.AddStream("a", []backtest.DataEvent{candle("AAA", base.Add(time.Hour))})
+	d.AddStream("b", []backtest.DataEvent{candle("BBB", base)})
+	d.SortStream()
+
+	stream := d.Stream()
+	if len(stream) != 2 {
+		t.Fatalf("len(Stream()) = %d, want 2", len(stream))
+	}
+	if stream[0].Symbol() != "BBB" || stream[1].Symbol() != "AAA" {
+		t.Errorf("Stream() order = [%s, %s], want [BBB, AAA] (earliest first)", stream[0].Symbol(), stream[1].Symbol())
+	}
+}
+
+func TestSortStreamBreaksTimeTiesByPriority(t *testing.T) {
+	d := &backtest.Data{}
+	d.RegisterSource("trades", 10)
+	d.RegisterSource("quotes", 0)
+
+	t0 := time.Now()
+	d.AddStream("quotes", []backtest.DataEvent{candle("AAA", t0)})
+	d.AddStream("trades", []backtest.DataEvent{candle("AAA", t0)})
+	d.SortStream()
+
+	stream := d.Stream()
+	if len(stream) != 2 {
+		t.Fatalf("len(Stream()) = %d, want 2", len(stream))
+	}
+	first, ok := stream[0].(backtest.SourcedEvent)
+	if !ok {
+		t.Fatalf("Stream()[0] type = %T, want backtest.SourcedEvent", stream[0])
+	}
+	if first.Source() != "trades" {
+		t.Errorf("Stream()[0].Source() = %q, want %q (higher-priority source first on a time tie)", first.Source(), "trades")
+	}
+}
+
+func TestSortStreamSortsLegacySetStream(t *testing.T) {
+	d := &backtest.Data{}
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Assembled the legacy way: concatenated per-symbol runs, globally
+	// unsorted across symbols.
+	d.SetStream([]backtest.DataEvent{
+		candle("AAA", base.Add(1*time.Hour)),
+		candle("AAA", base.Add(3*time.Hour)),
+		candle("BBB", base.Add(0*time.Hour)),
+		candle("BBB", base.Add(2*time.Hour)),
+	})
+	d.SortStream()
+
+	stream := d.Stream()
+	for i := 1; i < len(stream); i++ {
+		if stream[i].Time().Before(stream[i-1].Time()) {
+			t.Fatalf("Stream()[%d].Time() = %v is before Stream()[%d].Time() = %v, want non-decreasing order",
+				i, stream[i].Time(), i-1, stream[i-1].Time())
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package backtest
+
+import (
+	"fmt"
+	"time"
+)
+
+// StreamCursor is an opaque, cheap-to-copy snapshot of a Data's stream
+// position, produced by Snapshot and consumed by Restore. It lets a
+// driver fork a backtest at any point it has reached — for parameter
+// sweeps, walk-forward validation windows, or bootstrap resampling — and
+// replay forward from there without reloading or resorting the data.
+type StreamCursor struct {
+	stream        []DataEvent
+	streamHistory []DataEvent
+	pending       []DataEvent
+	runs          []*eventRun
+
+	latest map[string]DataEvent
+	list   map[string][]DataEvent
+
+	sourcePriority map[string]int
+	seq            int
+}
+
+// Snapshot captures d's current stream position. The stream, history and
+// pending slices are copied by header only (O(1)): Next only reslices or
+// appends to them and SortStream replaces the stream outright, so neither
+// mutates an already-revealed element and sharing their backing arrays is
+// safe. The latest/list/
+// sourcePriority maps are shallow-copied (O(distinct symbols), not
+// O(events)) so that later writes to d's maps — or to a Data restored
+// from this cursor — can't retroactively change the snapshot.
+//
+// The bloom accelerator is intentionally left out of the cursor: it is a
+// best-effort index, not authoritative state, so it is simplest to just
+// let it keep growing across forks rather than fork it too.
+func (d *Data) Snapshot() StreamCursor {
+	return StreamCursor{
+		stream:         d.stream,
+		streamHistory:  d.streamHistory,
+		pending:        d.pending,
+		runs:           append([]*eventRun(nil), d.runs...),
+		latest:         cloneEventMap(d.latest),
+		list:           cloneEventListMap(d.list),
+		sourcePriority: clonePriorityMap(d.sourcePriority),
+		seq:            d.seq,
+	}
+}
+
+// Restore rewinds d to the position captured by cursor. cursor itself is
+// left untouched, so it can be restored from more than once — e.g. to
+// fan a single snapshot out into several parameter-sweep or bootstrap
+// runs.
+func (d *Data) Restore(cursor StreamCursor) error {
+	d.stream = cursor.stream
+	d.streamHistory = cursor.streamHistory
+	d.pending = cursor.pending
+	d.runs = append([]*eventRun(nil), cursor.runs...)
+	d.latest = cloneEventMap(cursor.latest)
+	d.list = cloneEventListMap(cursor.list)
+	d.sourcePriority = clonePriorityMap(cursor.sourcePriority)
+	d.seq = cursor.seq
+	return nil
+}
+
+// ResetTo rewinds d so that exactly the events up to and including t have
+// been consumed, as if Next had been called that many times from a full
+// Reset. Unlike Reset, which always rewinds to the very beginning, this
+// lets a driver replay a previously consumed window of history without
+// reloading or reprocessing the data it has already stepped past.
+//
+// ResetTo returns an error under ModeOHLC or ModeTick, whether or not a
+// bar is partway through being exploded into sub-events: streamHistory holds the exploded SubEvents rather than the original
+// bars, and SubEvent itself implements BarEvent, so rebuilding d.stream
+// from streamHistory would hand whole sub-events back to Next to be
+// re-exploded a second time. Use ModeBarClose, or snapshot/restore via
+// StreamCursor instead, when ResetTo is needed under ModeOHLC/ModeTick.
+func (d *Data) ResetTo(t time.Time) error {
+	if d.Mode != ModeBarClose {
+		return fmt.Errorf("backtest: ResetTo is only supported under ModeBarClose, not %v", d.Mode)
+	}
+	if len(d.pending) != 0 {
+		return fmt.Errorf("backtest: ResetTo called with a bar partway through exploding into sub-events")
+	}
+
+	all := append(append([]DataEvent{}, d.streamHistory...), d.stream...)
+
+	d.latest = nil
+	d.list = nil
+	d.blooms = nil
+
+	var history, rest []DataEvent
+	for i, event := range all {
+		if event.Time().After(t) {
+			rest = all[i:]
+			break
+		}
+		history = append(history, event)
+		d.updateLatest(event)
+		d.updateList(event)
+	}
+
+	d.streamHistory = history
+	d.stream = rest
+	return nil
+}
+
+func cloneEventMap(m map[string]DataEvent) map[string]DataEvent {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]DataEvent, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+func cloneEventListMap(m map[string][]DataEvent) map[string][]DataEvent {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string][]DataEvent, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+func clonePriorityMap(m map[string]int) map[string]int {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]int, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
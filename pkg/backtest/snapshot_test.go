@@ -0,0 +1,86 @@
+package backtest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/finbotsdev/gobacktest/pkg/backtest"
+	"github.com/finbotsdev/gobacktest/pkg/backtest/kline"
+)
+
+func TestSnapshotRestoreRoundTrip(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := &backtest.Data{}
+	d.SetStream([]backtest.DataEvent{
+		candle("AAA", base),
+		candle("AAA", base.Add(time.Hour)),
+		candle("AAA", base.Add(2*time.Hour)),
+	})
+
+	if _, ok := d.Next(); !ok {
+		t.Fatal("Next() 1st call: ok = false, want true")
+	}
+	cursor := d.Snapshot()
+
+	if _, ok := d.Next(); !ok {
+		t.Fatal("Next() 2nd call: ok = false, want true")
+	}
+	if _, ok := d.Next(); !ok {
+		t.Fatal("Next() 3rd call: ok = false, want true")
+	}
+	if _, ok := d.Next(); ok {
+		t.Fatal("Next() 4th call: ok = true, want false (stream exhausted)")
+	}
+
+	if err := d.Restore(cursor); err != nil {
+		t.Fatalf("Restore() error = %v, want nil", err)
+	}
+
+	if len(d.Stream()) != 2 {
+		t.Fatalf("len(Stream()) after Restore = %d, want 2 (the two bars not yet consumed at Snapshot time)", len(d.Stream()))
+	}
+	if len(d.History()) != 1 {
+		t.Fatalf("len(History()) after Restore = %d, want 1 (the one bar consumed before Snapshot)", len(d.History()))
+	}
+}
+
+func TestResetToRewindsToWindow(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := &backtest.Data{}
+	d.SetStream([]backtest.DataEvent{
+		candle("AAA", base),
+		candle("AAA", base.Add(time.Hour)),
+		candle("AAA", base.Add(2*time.Hour)),
+	})
+
+	for i := 0; i < 3; i++ {
+		if _, ok := d.Next(); !ok {
+			t.Fatalf("Next() call %d: ok = false, want true", i)
+		}
+	}
+
+	if err := d.ResetTo(base.Add(time.Hour)); err != nil {
+		t.Fatalf("ResetTo() error = %v, want nil", err)
+	}
+
+	if len(d.History()) != 2 {
+		t.Fatalf("len(History()) after ResetTo = %d, want 2 (events up to and including the target time)", len(d.History()))
+	}
+	if len(d.Stream()) != 1 {
+		t.Fatalf("len(Stream()) after ResetTo = %d, want 1 (the event after the target time)", len(d.Stream()))
+	}
+}
+
+func TestResetToRejectsNonBarCloseMode(t *testing.T) {
+	d := &backtest.Data{Mode: backtest.ModeOHLC}
+	bar := kline.NewCandle("AAA", kline.Interval1Hour, time.Now(), 1, 4, 0, 3, 10)
+	d.SetStream([]backtest.DataEvent{bar})
+
+	if _, ok := d.Next(); !ok {
+		t.Fatal("Next() call: ok = false, want true")
+	}
+
+	if err := d.ResetTo(time.Now()); err == nil {
+		t.Fatal("ResetTo() under ModeOHLC: error = nil, want an error (streamHistory holds sub-events, not bars)")
+	}
+}